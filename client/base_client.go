@@ -50,6 +50,9 @@ type baseClient struct {
 	gRPCDialOptions []grpc.DialOption
 
 	timeout time.Duration
+
+	tsoQueuePolicy  TSOQueuePolicy
+	tsoQueueTimeout time.Duration
 }
 
 // SecurityOption records options about tls
@@ -76,6 +79,27 @@ func WithCustomTimeoutOption(timeout time.Duration) ClientOption {
 	}
 }
 
+// TSOQueuePolicy decides what GetTSAsync does when the tso request queue is full.
+type TSOQueuePolicy int
+
+const (
+	// TSOQueueBlock blocks the caller until the queue has room. It is the default policy.
+	TSOQueueBlock TSOQueuePolicy = iota
+	// TSOQueueFailFast fails the request immediately.
+	TSOQueueFailFast
+	// TSOQueueTimeout waits for the queue at most the given timeout before failing the request.
+	TSOQueueTimeout
+)
+
+// WithTSOQueuePolicy configures the client with the policy used when the tso
+// request queue is full. The timeout is only used by TSOQueueTimeout.
+func WithTSOQueuePolicy(policy TSOQueuePolicy, timeout time.Duration) ClientOption {
+	return func(c *baseClient) {
+		c.tsoQueuePolicy = policy
+		c.tsoQueueTimeout = timeout
+	}
+}
+
 // newBaseClient returns a new baseClient.
 func newBaseClient(ctx context.Context, urls []string, security SecurityOption, opts ...ClientOption) (*baseClient, error) {
 	ctx1, cancel := context.WithCancel(ctx)
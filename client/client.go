@@ -21,6 +21,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
@@ -71,6 +72,10 @@ type Client interface {
 	// The store may expire later. Caller is responsible for caching and taking care
 	// of store change.
 	GetAllStores(ctx context.Context, opts ...GetStoreOption) ([]*metapb.Store, error)
+	// GetClusterConfig gets the cluster meta, including the max peer count, from pd.
+	// The config may change later. Caller is responsible for caching and
+	// refreshing it.
+	GetClusterConfig(ctx context.Context) (*metapb.Cluster, error)
 	// Update GC safe point. TiKV will check it and do GC themselves if necessary.
 	// If the given safePoint is less than the current one, it will not be updated.
 	// Returns the new safePoint after updating.
@@ -86,10 +91,18 @@ type Client interface {
 	ScatterRegion(ctx context.Context, regionID uint64) error
 	// GetOperator gets the status of operator of the specified region.
 	GetOperator(ctx context.Context, regionID uint64) (*pdpb.GetOperatorResponse, error)
+	// Stats returns the current statistics of the client.
+	Stats() Stats
 	// Close closes the client.
 	Close()
 }
 
+// Stats is the statistics of a client.
+type Stats struct {
+	// TSOQueueLength is the number of tso requests waiting in the queue.
+	TSOQueueLength int
+}
+
 // GetStoreOp represents available options when getting stores.
 type GetStoreOp struct {
 	excludeTombstone bool
@@ -126,6 +139,14 @@ var (
 	errClosing = errors.New("[pd] closing")
 	// errTSOLength is returned when the number of response timestamps is inconsistent with request.
 	errTSOLength = errors.New("[pd] tso length in rpc response is incorrect")
+	// ErrClusterNotBootstrapped is returned when PD refuses to allocate
+	// timestamps or to get the cluster config because the cluster is not
+	// bootstrapped yet.
+	ErrClusterNotBootstrapped = errors.New("[pd] cluster is not bootstrapped")
+	// ErrClientBusy is returned when the tso request queue is full and the queue policy does not block.
+	ErrClientBusy = errors.New("[pd] client is busy, tso request queue is full")
+	// ErrInvalidTimestamp is returned when a finished tso request carries a zero physical time.
+	ErrInvalidTimestamp = errors.New("[pd] invalid timestamp")
 )
 
 type client struct {
@@ -317,11 +338,24 @@ func (c *client) processTSORequests(stream pdpb.PD_TsoClient, requests []*tsoReq
 		return err
 	}
 	resp, err := stream.Recv()
+	failpoint.Inject("tsoStreamRecvFail", func() {
+		err = errors.New("mock tso stream failure")
+	})
 	if err != nil {
 		err = errors.WithStack(err)
 		c.finishTSORequest(requests, 0, 0, err)
 		return err
 	}
+	if herr := resp.GetHeader().GetError(); herr != nil {
+		// The stream is healthy when the cluster is not bootstrapped, keep it.
+		if herr.GetType() == pdpb.ErrorType_NOT_BOOTSTRAPPED {
+			c.finishTSORequest(requests, 0, 0, errors.WithStack(ErrClusterNotBootstrapped))
+			return nil
+		}
+		err = errors.Errorf("[pd] tso response error: %s", herr.GetMessage())
+		c.finishTSORequest(requests, 0, 0, err)
+		return err
+	}
 	requestDurationTSO.Observe(time.Since(start).Seconds())
 	tsoBatchSize.Observe(float64(count))
 
@@ -410,11 +444,45 @@ func (c *client) GetTSAsync(ctx context.Context) TSFuture {
 	req := tsoReqPool.Get().(*tsoRequest)
 	req.ctx = ctx
 	req.start = time.Now()
-	c.tsoRequests <- req
+	req.physical, req.logical = 0, 0
+	c.dispatchTSORequest(req)
 
 	return req
 }
 
+// dispatchTSORequest sends the request to the tso loop according to the tso
+// queue policy. A rejected request is finished with ErrClientBusy. A request
+// waiting for the queue is also finished if the caller or the client is done.
+func (c *client) dispatchTSORequest(req *tsoRequest) {
+	switch c.tsoQueuePolicy {
+	case TSOQueueFailFast:
+		select {
+		case c.tsoRequests <- req:
+		default:
+			req.done <- errors.WithStack(ErrClientBusy)
+		}
+	case TSOQueueTimeout:
+		select {
+		case c.tsoRequests <- req:
+			return
+		default:
+		}
+		timer := time.NewTimer(c.tsoQueueTimeout)
+		defer timer.Stop()
+		select {
+		case c.tsoRequests <- req:
+		case <-timer.C:
+			req.done <- errors.WithStack(ErrClientBusy)
+		case <-req.ctx.Done():
+			req.done <- errors.WithStack(req.ctx.Err())
+		case <-c.ctx.Done():
+			req.done <- errors.WithStack(errClosing)
+		}
+	default:
+		c.tsoRequests <- req
+	}
+}
+
 // TSFuture is a future which promises to return a TSO.
 type TSFuture interface {
 	// Wait gets the physical and logical time, it would block caller if data is not available yet.
@@ -435,6 +503,10 @@ func (req *tsoRequest) Wait() (physical int64, logical int64, err error) {
 			return 0, 0, err
 		}
 		physical, logical = req.physical, req.logical
+		if physical <= 0 {
+			cmdFailDurationTSO.Observe(time.Since(req.start).Seconds())
+			return 0, 0, errors.WithStack(ErrInvalidTimestamp)
+		}
 		now := time.Now()
 		cmdDurationWait.Observe(now.Sub(start).Seconds())
 		cmdDurationTSO.Observe(now.Sub(req.start).Seconds())
@@ -648,6 +720,39 @@ func (c *client) GetAllStores(ctx context.Context, opts ...GetStoreOption) ([]*m
 	return stores, nil
 }
 
+func (c *client) GetClusterConfig(ctx context.Context) (*metapb.Cluster, error) {
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span = opentracing.StartSpan("pdclient.GetClusterConfig", opentracing.ChildOf(span.Context()))
+		defer span.Finish()
+	}
+	start := time.Now()
+	defer func() { cmdDurationGetClusterConfig.Observe(time.Since(start).Seconds()) }()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	resp, err := c.leaderClient().GetClusterConfig(ctx, &pdpb.GetClusterConfigRequest{
+		Header: c.requestHeader(),
+	})
+	cancel()
+
+	if err != nil {
+		cmdFailedDurationGetClusterConfig.Observe(time.Since(start).Seconds())
+		c.ScheduleCheckLeader()
+		return nil, errors.WithStack(err)
+	}
+	if herr := resp.GetHeader().GetError(); herr != nil {
+		cmdFailedDurationGetClusterConfig.Observe(time.Since(start).Seconds())
+		if herr.GetType() == pdpb.ErrorType_NOT_BOOTSTRAPPED {
+			return nil, errors.WithStack(ErrClusterNotBootstrapped)
+		}
+		return nil, errors.Errorf("[pd] get cluster config failed: %s", herr.GetMessage())
+	}
+	cluster := resp.GetCluster()
+	if cluster == nil {
+		return nil, errors.New("[pd] cluster field in rpc response not set")
+	}
+	return cluster, nil
+}
+
 func (c *client) UpdateGCSafePoint(ctx context.Context, safePoint uint64) (uint64, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		span = opentracing.StartSpan("pdclient.UpdateGCSafePoint", opentracing.ChildOf(span.Context()))
@@ -740,6 +845,12 @@ func (c *client) GetOperator(ctx context.Context, regionID uint64) (*pdpb.GetOpe
 	})
 }
 
+func (c *client) Stats() Stats {
+	return Stats{
+		TSOQueueLength: len(c.tsoRequests),
+	}
+}
+
 func (c *client) requestHeader() *pdpb.RequestHeader {
 	return &pdpb.RequestHeader{
 		ClusterId: c.clusterID,
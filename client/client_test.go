@@ -19,6 +19,7 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/testutil"
 	"go.uber.org/goleak"
@@ -45,6 +46,114 @@ func (s *testClientSuite) TestTsLessEqual(c *C) {
 	c.Assert(tsLessEqual(9, 6, 9, 8), IsTrue)
 }
 
+func (s *testClientSuite) TestTSOWaitRejectsZeroTimestamp(c *C) {
+	newReq := func() *tsoRequest {
+		req := tsoReqPool.Get().(*tsoRequest)
+		req.ctx = context.Background()
+		req.start = time.Now()
+		req.physical, req.logical = 0, 0
+		return req
+	}
+
+	cli := &client{}
+	// A failed batch must not leak the zero timestamp.
+	req := newReq()
+	cli.finishTSORequest([]*tsoRequest{req}, 0, 0, errClosing)
+	physical, logical, err := req.Wait()
+	c.Assert(err, NotNil)
+	c.Assert(physical, Equals, int64(0))
+	c.Assert(logical, Equals, int64(0))
+
+	// A zero timestamp without error is reported as invalid.
+	req = newReq()
+	cli.finishTSORequest([]*tsoRequest{req}, 0, 0, nil)
+	_, _, err = req.Wait()
+	c.Assert(errors.Cause(err), Equals, ErrInvalidTimestamp)
+
+	req = newReq()
+	cli.finishTSORequest([]*tsoRequest{req}, 10, 1, nil)
+	physical, logical, err = req.Wait()
+	c.Assert(err, IsNil)
+	c.Assert(physical, Equals, int64(10))
+	c.Assert(logical, Equals, int64(1))
+}
+
+type mockTSOStream struct {
+	grpc.ClientStream
+	resp *pdpb.TsoResponse
+}
+
+func (s *mockTSOStream) Send(*pdpb.TsoRequest) error { return nil }
+
+func (s *mockTSOStream) Recv() (*pdpb.TsoResponse, error) { return s.resp, nil }
+
+func (s *testClientSuite) TestTSONotBootstrapped(c *C) {
+	stream := &mockTSOStream{resp: &pdpb.TsoResponse{
+		Header: &pdpb.ResponseHeader{Error: &pdpb.Error{Type: pdpb.ErrorType_NOT_BOOTSTRAPPED}},
+	}}
+	req := tsoReqPool.Get().(*tsoRequest)
+	req.ctx = context.Background()
+	req.start = time.Now()
+
+	// The request fails but the stream is kept.
+	cli := &client{baseClient: &baseClient{}}
+	err := cli.processTSORequests(stream, []*tsoRequest{req}, nil)
+	c.Assert(err, IsNil)
+	_, _, err = req.Wait()
+	c.Assert(errors.Cause(err), Equals, ErrClusterNotBootstrapped)
+}
+
+func (s *testClientSuite) TestTSOQueuePolicy(c *C) {
+	newClient := func(policy TSOQueuePolicy, timeout time.Duration) *client {
+		ctx, cancel := context.WithCancel(context.Background())
+		cli := &client{
+			baseClient: &baseClient{
+				ctx:             ctx,
+				cancel:          cancel,
+				tsoQueuePolicy:  policy,
+				tsoQueueTimeout: timeout,
+			},
+			tsoRequests: make(chan *tsoRequest, 1),
+		}
+		// Fill the queue, nothing consumes it.
+		cli.tsoRequests <- &tsoRequest{}
+		return cli
+	}
+	c.Assert(newClient(TSOQueueBlock, 0).Stats().TSOQueueLength, Equals, 1)
+
+	cli := newClient(TSOQueueFailFast, 0)
+	_, _, err := cli.GetTSAsync(context.Background()).Wait()
+	c.Assert(errors.Cause(err), Equals, ErrClientBusy)
+
+	cli = newClient(TSOQueueTimeout, 100*time.Millisecond)
+	start := time.Now()
+	_, _, err = cli.GetTSAsync(context.Background()).Wait()
+	c.Assert(errors.Cause(err), Equals, ErrClientBusy)
+	c.Assert(time.Since(start), GreaterEqual, 100*time.Millisecond)
+
+	// The request is queued once there is room.
+	cli = newClient(TSOQueueTimeout, time.Second)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-cli.tsoRequests
+	}()
+	cli.GetTSAsync(context.Background())
+	c.Assert(cli.tsoRequests, HasLen, 1)
+
+	// The caller does not wait for the timeout once its context is done.
+	cli = newClient(TSOQueueTimeout, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = cli.GetTSAsync(ctx).Wait()
+	c.Assert(errors.Cause(err), Equals, context.DeadlineExceeded)
+
+	// Nor does it once the client is closing.
+	cli = newClient(TSOQueueTimeout, time.Minute)
+	time.AfterFunc(50*time.Millisecond, cli.cancel)
+	_, _, err = cli.GetTSAsync(context.Background()).Wait()
+	c.Assert(errors.Cause(err), Equals, errClosing)
+}
+
 func (s *testClientSuite) TestUpdateURLs(c *C) {
 	members := []*pdpb.Member{
 		{Name: "pd4", ClientUrls: []string{"tmp//pd4"}},
@@ -64,6 +64,7 @@ var (
 	cmdDurationScanRegions              = cmdDuration.WithLabelValues("scan_regions")
 	cmdDurationGetStore                 = cmdDuration.WithLabelValues("get_store")
 	cmdDurationGetAllStores             = cmdDuration.WithLabelValues("get_all_stores")
+	cmdDurationGetClusterConfig         = cmdDuration.WithLabelValues("get_cluster_config")
 	cmdDurationUpdateGCSafePoint        = cmdDuration.WithLabelValues("update_gc_safe_point")
 	cmdDurationUpdateServiceGCSafePoint = cmdDuration.WithLabelValues("update_service_gc_safe_point")
 	cmdDurationScatterRegion            = cmdDuration.WithLabelValues("scatter_region")
@@ -76,6 +77,7 @@ var (
 	cmdFailedDurationScanRegions              = cmdFailedDuration.WithLabelValues("scan_regions")
 	cmdFailedDurationGetStore                 = cmdFailedDuration.WithLabelValues("get_store")
 	cmdFailedDurationGetAllStores             = cmdFailedDuration.WithLabelValues("get_all_stores")
+	cmdFailedDurationGetClusterConfig         = cmdFailedDuration.WithLabelValues("get_cluster_config")
 	cmdFailedDurationUpdateGCSafePoint        = cmdFailedDuration.WithLabelValues("update_gc_safe_point")
 	cmdFailedDurationUpdateServiceGCSafePoint = cmdFailedDuration.WithLabelValues("update_service_gc_safe_point")
 	requestDurationTSO                        = requestDuration.WithLabelValues("tso")
@@ -32,6 +32,9 @@ const (
 	RedirectorHeader    = "PD-Redirector"
 	AllowFollowerHandle = "PD-Allow-follower-handle"
 	FollowerHandle      = "PD-Follwer-handle"
+	// ForwardedForHeader carries the address of the client which sent the
+	// request to the redirecting server.
+	ForwardedForHeader = "PD-Forwarded-For"
 )
 
 const (
@@ -106,6 +109,7 @@ func (h *redirector) ServeHTTP(w http.ResponseWriter, r *http.Request, next http
 	}
 
 	r.Header.Set(RedirectorHeader, h.s.Name())
+	r.Header.Set(ForwardedForHeader, r.RemoteAddr)
 
 	leader := h.s.GetMember().GetLeader()
 	if leader == nil {
@@ -0,0 +1,86 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/tikv/pd/pkg/apiutil/serverapi"
+	"github.com/tikv/pd/server"
+	"github.com/urfave/negroni"
+)
+
+// maxAuditBodySize is the max size of the request body kept in an audit entry.
+const maxAuditBodySize = 4096
+
+type auditMiddleware struct {
+	auditor server.Auditor
+}
+
+// newAuditMiddleware returns a middleware that records every request
+// which is not read-only with the given auditor.
+func newAuditMiddleware(auditor server.Auditor) negroni.Handler {
+	return &auditMiddleware{auditor: auditor}
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		next(w, r)
+		return
+	}
+
+	start := time.Now()
+	params := readAuditBody(r)
+	rw := negroni.NewResponseWriter(w)
+	next(rw, r)
+	m.auditor.Audit(&server.AuditEntry{
+		Time:         start,
+		RemoteAddr:   r.RemoteAddr,
+		ForwardedFor: forwardedFor(r),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		Query:        r.URL.RawQuery,
+		Params:       params,
+		StatusCode:   rw.Status(),
+		Duration:     time.Since(start),
+	})
+}
+
+// readAuditBody returns the head of the request body, and puts it back so
+// the handler still reads the whole body.
+func readAuditBody(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	head, _ := ioutil.ReadAll(io.LimitReader(r.Body, maxAuditBodySize))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	return string(head)
+}
+
+// forwardedFor returns the client address of a request redirected by a
+// follower. Anyone can set the header, so it is kept apart from RemoteAddr.
+func forwardedFor(r *http.Request) string {
+	if len(r.Header.Get(serverapi.RedirectorHeader)) == 0 {
+		return ""
+	}
+	return r.Header.Get(serverapi.ForwardedForHeader)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/apiutil/serverapi"
+	"github.com/tikv/pd/server"
+	"github.com/urfave/negroni"
+)
+
+var _ = Suite(&testAuditSuite{})
+
+type testAuditSuite struct{}
+
+type mockAuditor struct {
+	entries []*server.AuditEntry
+}
+
+func (a *mockAuditor) Audit(entry *server.AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func (s *testAuditSuite) TestAuditMiddleware(c *C) {
+	auditor := &mockAuditor{}
+	var body []byte
+	handler := negroni.New(
+		newAuditMiddleware(auditor),
+		negroni.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})),
+	)
+
+	// Read-only requests are not recorded.
+	req := httptest.NewRequest(http.MethodGet, "/pd/api/v1/stores", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 0)
+
+	req = httptest.NewRequest(http.MethodPost, "/pd/api/v1/store/1/state?state=Offline", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 1)
+	entry := auditor.entries[0]
+	c.Assert(entry.Method, Equals, http.MethodPost)
+	c.Assert(entry.Path, Equals, "/pd/api/v1/store/1/state")
+	c.Assert(entry.Query, Equals, "state=Offline")
+	c.Assert(entry.StatusCode, Equals, http.StatusOK)
+	c.Assert(entry.RemoteAddr, Equals, req.RemoteAddr)
+
+	req = httptest.NewRequest(http.MethodDelete, "/pd/api/v1/store/1", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 2)
+	c.Assert(auditor.entries[1].StatusCode, Equals, http.StatusInternalServerError)
+
+	// The body is recorded as the parameters, and the handler still reads it.
+	req = httptest.NewRequest(http.MethodPost, "/pd/api/v1/config", strings.NewReader(`{"max-replicas":5}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 3)
+	c.Assert(auditor.entries[2].Params, Equals, `{"max-replicas":5}`)
+	c.Assert(string(body), Equals, `{"max-replicas":5}`)
+
+	// Only the head of a large body is recorded.
+	large := strings.Repeat("a", maxAuditBodySize*2)
+	req = httptest.NewRequest(http.MethodPost, "/pd/api/v1/config", strings.NewReader(large))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 4)
+	c.Assert(auditor.entries[3].Params, Equals, large[:maxAuditBodySize])
+	c.Assert(string(body), Equals, large)
+
+	// The request redirected by a follower keeps the address of the follower,
+	// the client address it claims is recorded apart.
+	req = httptest.NewRequest(http.MethodPost, "/pd/api/v1/config", nil)
+	req.Header.Set(serverapi.RedirectorHeader, "pd2")
+	req.Header.Set(serverapi.ForwardedForHeader, "10.0.0.1:34567")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 5)
+	c.Assert(auditor.entries[4].RemoteAddr, Equals, req.RemoteAddr)
+	c.Assert(auditor.entries[4].ForwardedFor, Equals, "10.0.0.1:34567")
+
+	// The header is ignored if the request is not redirected.
+	req = httptest.NewRequest(http.MethodPost, "/pd/api/v1/config", nil)
+	req.Header.Set(serverapi.ForwardedForHeader, "10.0.0.1:34567")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	c.Assert(auditor.entries, HasLen, 6)
+	c.Assert(auditor.entries[5].RemoteAddr, Equals, req.RemoteAddr)
+	c.Assert(auditor.entries[5].ForwardedFor, Equals, "")
+}
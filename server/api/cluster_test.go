@@ -67,6 +67,7 @@ func (s *testClusterSuite) TestGetClusterStatus(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(status.RaftBootstrapTime.IsZero(), IsTrue)
 	c.Assert(status.IsInitialized, IsFalse)
+	c.Assert(status.TsoRequireBootstrap, IsFalse)
 	now := time.Now()
 	mustBootstrapCluster(c, s.svr)
 	err = readJSON(testDialClient, url, &status)
@@ -79,3 +80,15 @@ func (s *testClusterSuite) TestGetClusterStatus(c *C) {
 	c.Assert(status.RaftBootstrapTime.After(now), IsTrue)
 	c.Assert(status.IsInitialized, IsTrue)
 }
+
+func (s *testClusterSuite) TestGetClusterStatusTsoRequireBootstrap(c *C) {
+	svr, cleanup := mustNewServer(c, func(cfg *config.Config) { cfg.TsoRequireBootstrap = true })
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+
+	url := fmt.Sprintf("%s%s/api/v1/cluster/status", svr.GetAddr(), apiPrefix)
+	status := cluster.Status{}
+	err := readJSON(testDialClient, url, &status)
+	c.Assert(err, IsNil)
+	c.Assert(status.TsoRequireBootstrap, IsTrue)
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AuditGRPCMethod is the method of the audit entries of gRPC requests.
+const AuditGRPCMethod = "gRPC"
+
+// AuditEntry is the record of a request which may change the cluster.
+type AuditEntry struct {
+	Time time.Time
+	// RemoteAddr is the address of the peer which sent the request.
+	RemoteAddr string
+	// ForwardedFor is the client address claimed by the PD member which
+	// redirected the request. It is not verified.
+	ForwardedFor string
+	Method       string
+	Path         string
+	Query        string
+	// Params are the key parameters of the request.
+	Params     string
+	StatusCode int
+	// Error is the type of the error in the response header, or the error
+	// returned by the handler.
+	Error    string
+	Duration time.Duration
+}
+
+// Auditor records the requests which may change the cluster.
+type Auditor interface {
+	Audit(entry *AuditEntry)
+}
+
+// LogAuditor writes the audit entries into the PD log, so the entries
+// are rotated with the log file.
+type LogAuditor struct{}
+
+// Audit implements Auditor.
+func (LogAuditor) Audit(entry *AuditEntry) {
+	log.Info("audit",
+		zap.Time("time", entry.Time),
+		zap.String("remote-addr", entry.RemoteAddr),
+		zap.String("forwarded-for", entry.ForwardedFor),
+		zap.String("method", entry.Method),
+		zap.String("path", entry.Path),
+		zap.String("query", entry.Query),
+		zap.String("params", entry.Params),
+		zap.Int("status-code", entry.StatusCode),
+		zap.String("error", entry.Error),
+		zap.Duration("duration", entry.Duration))
+}
+
+// auditGRPC records a gRPC request which may change the cluster. The path
+// is the name of the RPC and the status code is the gRPC code of err. PD
+// handlers refuse a request with an error in the response header, so the
+// error type is recorded from the header if err is nil.
+func (s *Server) auditGRPC(ctx context.Context, rpc string, params string, start time.Time, header *pdpb.ResponseHeader, err error) {
+	entry := &AuditEntry{
+		Time:       start,
+		Method:     AuditGRPCMethod,
+		Path:       rpc,
+		Params:     params,
+		StatusCode: int(status.Code(errors.Cause(err))),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		entry.RemoteAddr = p.Addr.String()
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if herr := header.GetError(); herr != nil {
+		entry.Error = herr.GetType().String()
+	}
+	entry.Duration = time.Since(start)
+	s.auditor.Audit(entry)
+}
+
+func regionIDs(regions []*metapb.Region) []uint64 {
+	ids := make([]uint64, 0, len(regions))
+	for _, region := range regions {
+		ids = append(ids, region.GetId())
+	}
+	return ids
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+)
+
+var _ = Suite(&testAuditSuite{})
+
+type testAuditSuite struct{}
+
+type mockAuditor struct {
+	entries []*AuditEntry
+}
+
+func (a *mockAuditor) Audit(entry *AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func (s *testAuditSuite) TestAuditGRPC(c *C) {
+	svr, cleanup, err := NewTestServer(c)
+	c.Assert(err, IsNil)
+	defer cleanup()
+	mustWaitLeader(c, []*Server{svr})
+
+	auditor := &mockAuditor{}
+	svr.auditor = auditor
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 34567}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	header := &pdpb.RequestHeader{ClusterId: svr.ClusterID()}
+
+	// The cluster is not bootstrapped, the request is refused in the header.
+	_, err = svr.PutClusterConfig(ctx, &pdpb.PutClusterConfigRequest{
+		Header:  header,
+		Cluster: &metapb.Cluster{Id: svr.ClusterID(), MaxPeerCount: 5},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(auditor.entries, HasLen, 1)
+	entry := auditor.entries[0]
+	c.Assert(entry.RemoteAddr, Equals, addr.String())
+	c.Assert(entry.Method, Equals, AuditGRPCMethod)
+	c.Assert(entry.Path, Equals, "PutClusterConfig")
+	c.Assert(entry.Params, Equals, "max-peer-count=5")
+	c.Assert(entry.StatusCode, Equals, int(codes.OK))
+	c.Assert(entry.Error, Equals, pdpb.ErrorType_NOT_BOOTSTRAPPED.String())
+
+	bootstrapReq := &pdpb.BootstrapRequest{
+		Header: header,
+		Store:  &metapb.Store{Id: 1, Address: "127.0.0.1:20160"},
+		Region: &metapb.Region{Id: 2, Peers: []*metapb.Peer{{Id: 3, StoreId: 1}}},
+	}
+	_, err = svr.Bootstrap(ctx, bootstrapReq)
+	c.Assert(err, IsNil)
+	c.Assert(auditor.entries, HasLen, 2)
+	entry = auditor.entries[1]
+	c.Assert(entry.Path, Equals, "Bootstrap")
+	c.Assert(entry.Params, Equals, "store-id=1 region-id=2")
+	c.Assert(entry.Error, Equals, "")
+
+	_, err = svr.Bootstrap(ctx, bootstrapReq)
+	c.Assert(err, IsNil)
+	c.Assert(auditor.entries, HasLen, 3)
+	c.Assert(auditor.entries[2].Error, Equals, pdpb.ErrorType_ALREADY_BOOTSTRAPPED.String())
+
+	_, err = svr.UpdateGCSafePoint(ctx, &pdpb.UpdateGCSafePointRequest{Header: header, SafePoint: 100})
+	c.Assert(err, IsNil)
+	c.Assert(auditor.entries, HasLen, 4)
+	entry = auditor.entries[3]
+	c.Assert(entry.Path, Equals, "UpdateGCSafePoint")
+	c.Assert(entry.Params, Equals, "safe-point=100")
+	c.Assert(entry.Error, Equals, "")
+
+	// The error returned by the handler is recorded.
+	_, err = svr.ScatterRegion(ctx, &pdpb.ScatterRegionRequest{Header: header, RegionId: 100})
+	c.Assert(err, NotNil)
+	c.Assert(auditor.entries, HasLen, 5)
+	entry = auditor.entries[4]
+	c.Assert(entry.Path, Equals, "ScatterRegion")
+	c.Assert(entry.Params, Equals, "region-id=100")
+	c.Assert(entry.Error, Equals, err.Error())
+
+	// The request with a mismatched cluster ID is rejected.
+	req := &pdpb.PutClusterConfigRequest{Header: &pdpb.RequestHeader{ClusterId: svr.ClusterID() + 1}}
+	_, err = svr.PutClusterConfig(ctx, req)
+	c.Assert(err, NotNil)
+	c.Assert(auditor.entries, HasLen, 6)
+	c.Assert(auditor.entries[5].StatusCode, Equals, int(codes.FailedPrecondition))
+}
@@ -126,6 +126,8 @@ type Status struct {
 	RaftBootstrapTime time.Time `json:"raft_bootstrap_time,omitempty"`
 	IsInitialized     bool      `json:"is_initialized"`
 	ReplicationStatus string    `json:"replication_status"`
+	// TsoRequireBootstrap shows whether TSO is only served after bootstrap.
+	TsoRequireBootstrap bool `json:"tso_require_bootstrap"`
 }
 
 // NewRaftCluster create a new cluster.
@@ -228,6 +230,10 @@ func (c *RaftCluster) Start(s Server) error {
 		return nil
 	}
 
+	if err := c.rebaseAllocator(s.GetAllocator()); err != nil {
+		return err
+	}
+
 	c.ruleManager = placement.NewRuleManager(c.storage)
 	if c.IsPlacementRulesEnabled() {
 		err = c.ruleManager.Initialize(c.opt.GetMaxReplicas(), c.opt.GetLocationLabels())
@@ -301,6 +307,29 @@ func (c *RaftCluster) LoadClusterInfo() (*RaftCluster, error) {
 	return c, nil
 }
 
+// rebaseAllocator fast-forwards the ID allocator past the IDs used by the
+// loaded stores, regions and peers, so a rewound counter (e.g. after restoring
+// etcd from a backup) never hands out a duplicated ID.
+func (c *RaftCluster) rebaseAllocator(alloc *id.AllocatorImpl) error {
+	var maxID uint64
+	for _, store := range c.core.GetStores() {
+		if store.GetID() > maxID {
+			maxID = store.GetID()
+		}
+	}
+	for _, region := range c.core.GetRegions() {
+		if region.GetID() > maxID {
+			maxID = region.GetID()
+		}
+		for _, peer := range region.GetPeers() {
+			if peer.GetId() > maxID {
+				maxID = peer.GetId()
+			}
+		}
+	}
+	return alloc.Rebase(maxID)
+}
+
 func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 	defer logutil.LogPanic()
 	defer c.wg.Done()
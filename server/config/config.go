@@ -85,6 +85,11 @@ type Config struct {
 	// TsoSaveInterval is the interval to save timestamp.
 	TsoSaveInterval typeutil.Duration `toml:"tso-save-interval" json:"tso-save-interval"`
 
+	// TsoRequireBootstrap makes PD refuse to allocate timestamps before the
+	// cluster is bootstrapped, so a client pointed at a wrong, empty PD fails
+	// instead of getting valid-looking timestamps.
+	TsoRequireBootstrap bool `toml:"tso-require-bootstrap" json:"tso-require-bootstrap"`
+
 	Metric metricutil.MetricConfig `toml:"metric" json:"metric"`
 
 	Schedule ScheduleConfig `toml:"schedule" json:"schedule"`
@@ -504,6 +509,9 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	}
 	adjustDuration(&c.TickInterval, defaultTickInterval)
 	adjustDuration(&c.ElectionInterval, defaultElectionInterval)
+	if err := c.validateIntervals(); err != nil {
+		return err
+	}
 
 	adjustString(&c.Metric.PushJob, c.Name)
 
@@ -537,6 +545,23 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	return nil
 }
 
+// validateIntervals checks the time related configurations after they are
+// adjusted, so that an invalid combination is reported before starting etcd.
+func (c *Config) validateIntervals() error {
+	if c.LeaderLease < 1 {
+		return errors.Errorf("lease %d is invalid, it should be at least 1 second", c.LeaderLease)
+	}
+	if c.TsoSaveInterval.Duration <= 0 {
+		return errors.Errorf("tso-save-interval %v is invalid, it should be positive", c.TsoSaveInterval.Duration)
+	}
+	// embed etcd requires the election timeout to be at least 5 times of the tick.
+	if 5*c.TickInterval.Duration > c.ElectionInterval.Duration {
+		return errors.Errorf("election-interval %v should be at least 5 times of tick-interval %v",
+			c.ElectionInterval.Duration, c.TickInterval.Duration)
+	}
+	return nil
+}
+
 func (c *Config) adjustLog(meta *configMetaData) {
 	if !meta.IsDefined("disable-error-verbose") {
 		c.Log.DisableErrorVerbose = defaultDisableErrorVerbose
@@ -220,6 +220,34 @@ address = "localhost:9090"
 	c.Assert(cfg.Metric.PushAddress, Equals, "localhost:9090")
 }
 
+func (s *testConfigSuite) TestValidateIntervals(c *C) {
+	tests := []struct {
+		cfgData string
+		hasErr  bool
+	}{
+		{"", false},
+		{"lease = 5", false},
+		{"lease = -1", true},
+		{`tso-save-interval = "-1s"`, true},
+		{`
+tick-interval = "100ms"
+election-interval = "500ms"
+`, false},
+		{`
+tick-interval = "1s"
+election-interval = "3s"
+`, true},
+	}
+
+	for _, t := range tests {
+		cfg := NewConfig()
+		meta, err := toml.Decode(t.cfgData, &cfg)
+		c.Assert(err, IsNil)
+		err = cfg.Adjust(&meta)
+		c.Assert(err != nil, Equals, t.hasErr, Commentf("config: %s", t.cfgData))
+	}
+}
+
 func (s *testConfigSuite) TestMigrateFlags(c *C) {
 	load := func(s string) (*Config, error) {
 		cfg := NewConfig()
@@ -14,8 +14,11 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"math"
+	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -46,6 +49,8 @@ const (
 	defaultFlushRegionRate = 3 * time.Second
 	// DefaultBatchSize is the batch size to save the regions to region storage.
 	defaultBatchSize = 100
+	// maxRegionPeerCount is the sanity cap of the peers of a region loaded from storage.
+	maxRegionPeerCount = 1024
 )
 
 // NewRegionStorage returns a region storage that is used to save regions.
@@ -129,7 +134,7 @@ func loadRegions(kv kv.Base, f func(region *RegionInfo) []*RegionInfo) error {
 	rangeLimit := maxKVRangeLimit
 	for {
 		startKey := regionPath(nextID)
-		_, res, err := kv.LoadRange(startKey, endKey, rangeLimit)
+		keys, res, err := kv.LoadRange(startKey, endKey, rangeLimit)
 		if err != nil {
 			if rangeLimit /= 2; rangeLimit >= minKVRangeLimit {
 				continue
@@ -137,13 +142,23 @@ func loadRegions(kv kv.Base, f func(region *RegionInfo) []*RegionInfo) error {
 			return err
 		}
 
-		for _, s := range res {
+		for i, s := range res {
+			// Always continue from the key, a corrupted value may decode to
+			// any ID and make the loop rescan the same range forever.
+			if nextID, err = nextRegionID(keys[i]); err != nil {
+				return err
+			}
 			region := &metapb.Region{}
 			if err := region.Unmarshal([]byte(s)); err != nil {
-				return errors.WithStack(err)
+				// Skip the corrupted entry so that one bad value does not block
+				// loading all the other regions.
+				log.Error("failed to unmarshal region, skip it", zap.String("key", keys[i]), zap.Error(err))
+				continue
+			}
+			if err := checkRegionMeta(region, nextID-1); err != nil {
+				log.Error("invalid region meta, skip it", zap.String("key", keys[i]), zap.Error(err))
+				continue
 			}
-
-			nextID = region.GetId() + 1
 			overlaps := f(NewRegionInfo(region, nil))
 			for _, item := range overlaps {
 				if err := deleteRegion(kv, item.GetMeta()); err != nil {
@@ -158,6 +173,34 @@ func loadRegions(kv kv.Base, f func(region *RegionInfo) []*RegionInfo) error {
 	}
 }
 
+// nextRegionID returns the region ID following the one encoded in the region key.
+func nextRegionID(key string) (uint64, error) {
+	id, err := strconv.ParseUint(path.Base(key), 10, 64)
+	if err != nil {
+		return 0, errors.Annotatef(err, "invalid region key %s", key)
+	}
+	return id + 1, nil
+}
+
+// checkRegionMeta checks whether the region loaded from the key of the given
+// region ID is sane.
+func checkRegionMeta(region *metapb.Region, id uint64) error {
+	if region.GetId() == 0 {
+		return errors.New("invalid zero region id")
+	}
+	if region.GetId() != id {
+		return errors.Errorf("region id %d does not match the key of region %d", region.GetId(), id)
+	}
+	if len(region.GetPeers()) > maxRegionPeerCount {
+		return errors.Errorf("region %d has too many peers %d", region.GetId(), len(region.GetPeers()))
+	}
+	startKey, endKey := region.GetStartKey(), region.GetEndKey()
+	if len(endKey) > 0 && bytes.Compare(startKey, endKey) >= 0 {
+		return errors.Errorf("region %d has invalid key range [%x, %x)", region.GetId(), startKey, endKey)
+	}
+	return nil
+}
+
 // FlushRegion saves the cache region to region storage.
 func (s *RegionStorage) FlushRegion() error {
 	s.mu.Lock()
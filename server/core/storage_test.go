@@ -126,7 +126,7 @@ func (s *testKVSuite) TestStoreWeight(c *C) {
 func mustSaveRegions(c *C, s *Storage, n int) []*metapb.Region {
 	regions := make([]*metapb.Region, 0, n)
 	for i := 0; i < n; i++ {
-		region := newTestRegionMeta(uint64(i))
+		region := newTestRegionMeta(uint64(i + 1))
 		regions = append(regions, region)
 	}
 
@@ -147,10 +147,71 @@ func (s *testKVSuite) TestLoadRegions(c *C) {
 
 	c.Assert(cache.GetRegionCount(), Equals, n)
 	for _, region := range cache.GetMetaRegions() {
-		c.Assert(region, DeepEquals, regions[region.GetId()])
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
 	}
 }
 
+func (s *testKVSuite) TestLoadRegionsSkipCorrupted(c *C) {
+	storage := NewStorage(kv.NewMemoryKV())
+	cache := NewRegionsInfo()
+
+	n := 10
+	regions := mustSaveRegions(c, storage, n)
+	// Corrupt one region and make another one have an invalid key range.
+	c.Assert(storage.Save(regionPath(3), "garbage"), IsNil)
+	invalid := newTestRegionMeta(5)
+	invalid.StartKey, invalid.EndKey = invalid.EndKey, invalid.StartKey
+	c.Assert(storage.SaveRegion(invalid), IsNil)
+	c.Assert(storage.LoadRegions(cache.SetRegion), IsNil)
+
+	c.Assert(cache.GetRegionCount(), Equals, n-2)
+	c.Assert(cache.GetRegion(3), IsNil)
+	c.Assert(cache.GetRegion(5), IsNil)
+	for _, region := range cache.GetMetaRegions() {
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
+	}
+
+	// An empty value decodes to region 0, and a value may be saved under the
+	// key of another region.
+	cache = NewRegionsInfo()
+	c.Assert(storage.Save(regionPath(6), ""), IsNil)
+	mismatched, err := newTestRegionMeta(2).Marshal()
+	c.Assert(err, IsNil)
+	c.Assert(storage.Save(regionPath(8), string(mismatched)), IsNil)
+	// Too many peers.
+	crowded := newTestRegionMeta(9)
+	for i := 0; i <= maxRegionPeerCount; i++ {
+		crowded.Peers = append(crowded.Peers, &metapb.Peer{Id: uint64(100 + i), StoreId: 1})
+	}
+	c.Assert(storage.SaveRegion(crowded), IsNil)
+	c.Assert(storage.LoadRegions(cache.SetRegion), IsNil)
+
+	c.Assert(cache.GetRegionCount(), Equals, n-5)
+	for _, id := range []uint64{3, 5, 6, 8, 9} {
+		c.Assert(cache.GetRegion(id), IsNil)
+	}
+	for _, region := range cache.GetMetaRegions() {
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
+	}
+}
+
+func (s *testKVSuite) TestLoadRegionsCorruptedBatchEnd(c *C) {
+	storage := NewStorage(&KVWithMaxRangeLimit{Base: kv.NewMemoryKV(), rangeLimit: 500})
+	cache := NewRegionsInfo()
+
+	n := 1000
+	mustSaveRegions(c, storage, n)
+	// Every batch ends with an entry which decodes to a smaller region ID, the
+	// loading must still move forward.
+	mismatched, err := newTestRegionMeta(1).Marshal()
+	c.Assert(err, IsNil)
+	for id := uint64(2); id <= uint64(n); id++ {
+		c.Assert(storage.Save(regionPath(id), string(mismatched)), IsNil)
+	}
+	c.Assert(storage.LoadRegions(cache.SetRegion), IsNil)
+	c.Assert(cache.GetRegionCount(), Equals, 1)
+}
+
 func (s *testKVSuite) TestLoadRegionsToCache(c *C) {
 	storage := NewStorage(kv.NewMemoryKV())
 	cache := NewRegionsInfo()
@@ -161,7 +222,7 @@ func (s *testKVSuite) TestLoadRegionsToCache(c *C) {
 
 	c.Assert(cache.GetRegionCount(), Equals, n)
 	for _, region := range cache.GetMetaRegions() {
-		c.Assert(region, DeepEquals, regions[region.GetId()])
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
 	}
 
 	n = 20
@@ -179,7 +240,7 @@ func (s *testKVSuite) TestLoadRegionsExceedRangeLimit(c *C) {
 	c.Assert(storage.LoadRegions(cache.SetRegion), IsNil)
 	c.Assert(cache.GetRegionCount(), Equals, n)
 	for _, region := range cache.GetMetaRegions() {
-		c.Assert(region, DeepEquals, regions[region.GetId()])
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
 	}
 }
 
@@ -90,6 +90,18 @@ func (s *Server) Tso(stream pdpb.PD_TsoServer) error {
 		if request.GetHeader().GetClusterId() != s.clusterID {
 			return status.Errorf(codes.FailedPrecondition, "mismatch cluster id, need %d but got %d", s.clusterID, request.GetHeader().GetClusterId())
 		}
+		if s.cfg.TsoRequireBootstrap {
+			bootstrapped, err := s.isClusterBootstrapped()
+			if err != nil {
+				return status.Errorf(codes.Unknown, err.Error())
+			}
+			if !bootstrapped {
+				if err := stream.Send(&pdpb.TsoResponse{Header: s.notBootstrappedHeader()}); err != nil {
+					return errors.WithStack(err)
+				}
+				continue
+			}
+		}
 		count := request.GetCount()
 		ts, err := s.tsoAllocator.GenerateTSO(count)
 		if err != nil {
@@ -113,7 +125,10 @@ func (s *Server) Tso(stream pdpb.PD_TsoServer) error {
 }
 
 // Bootstrap implements gRPC PDServer.
-func (s *Server) Bootstrap(ctx context.Context, request *pdpb.BootstrapRequest) (*pdpb.BootstrapResponse, error) {
+func (s *Server) Bootstrap(ctx context.Context, request *pdpb.BootstrapRequest) (resp *pdpb.BootstrapResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "Bootstrap", fmt.Sprintf("store-id=%d region-id=%d", request.GetStore().GetId(), request.GetRegion().GetId()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -208,7 +223,10 @@ func checkStore(rc *cluster.RaftCluster, storeID uint64) *pdpb.Error {
 }
 
 // PutStore implements gRPC PDServer.
-func (s *Server) PutStore(ctx context.Context, request *pdpb.PutStoreRequest) (*pdpb.PutStoreResponse, error) {
+func (s *Server) PutStore(ctx context.Context, request *pdpb.PutStoreRequest) (resp *pdpb.PutStoreResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "PutStore", fmt.Sprintf("store-id=%d address=%s", request.GetStore().GetId(), request.GetStore().GetAddress()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -515,7 +533,10 @@ func (s *Server) ScanRegions(ctx context.Context, request *pdpb.ScanRegionsReque
 }
 
 // AskSplit implements gRPC PDServer.
-func (s *Server) AskSplit(ctx context.Context, request *pdpb.AskSplitRequest) (*pdpb.AskSplitResponse, error) {
+func (s *Server) AskSplit(ctx context.Context, request *pdpb.AskSplitRequest) (resp *pdpb.AskSplitResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "AskSplit", fmt.Sprintf("region-id=%d", request.GetRegion().GetId()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -543,7 +564,10 @@ func (s *Server) AskSplit(ctx context.Context, request *pdpb.AskSplitRequest) (*
 }
 
 // AskBatchSplit implements gRPC PDServer.
-func (s *Server) AskBatchSplit(ctx context.Context, request *pdpb.AskBatchSplitRequest) (*pdpb.AskBatchSplitResponse, error) {
+func (s *Server) AskBatchSplit(ctx context.Context, request *pdpb.AskBatchSplitRequest) (resp *pdpb.AskBatchSplitResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "AskBatchSplit", fmt.Sprintf("region-id=%d split-count=%d", request.GetRegion().GetId(), request.GetSplitCount()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -575,7 +599,10 @@ func (s *Server) AskBatchSplit(ctx context.Context, request *pdpb.AskBatchSplitR
 }
 
 // ReportSplit implements gRPC PDServer.
-func (s *Server) ReportSplit(ctx context.Context, request *pdpb.ReportSplitRequest) (*pdpb.ReportSplitResponse, error) {
+func (s *Server) ReportSplit(ctx context.Context, request *pdpb.ReportSplitRequest) (resp *pdpb.ReportSplitResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "ReportSplit", fmt.Sprintf("left-region-id=%d right-region-id=%d", request.GetLeft().GetId(), request.GetRight().GetId()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -584,8 +611,7 @@ func (s *Server) ReportSplit(ctx context.Context, request *pdpb.ReportSplitReque
 	if rc == nil {
 		return &pdpb.ReportSplitResponse{Header: s.notBootstrappedHeader()}, nil
 	}
-	_, err := rc.HandleReportSplit(request)
-	if err != nil {
+	if _, err := rc.HandleReportSplit(request); err != nil {
 		return nil, status.Errorf(codes.Unknown, err.Error())
 	}
 
@@ -595,7 +621,10 @@ func (s *Server) ReportSplit(ctx context.Context, request *pdpb.ReportSplitReque
 }
 
 // ReportBatchSplit implements gRPC PDServer.
-func (s *Server) ReportBatchSplit(ctx context.Context, request *pdpb.ReportBatchSplitRequest) (*pdpb.ReportBatchSplitResponse, error) {
+func (s *Server) ReportBatchSplit(ctx context.Context, request *pdpb.ReportBatchSplitRequest) (resp *pdpb.ReportBatchSplitResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "ReportBatchSplit", fmt.Sprintf("region-ids=%v", regionIDs(request.GetRegions())), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -605,8 +634,7 @@ func (s *Server) ReportBatchSplit(ctx context.Context, request *pdpb.ReportBatch
 		return &pdpb.ReportBatchSplitResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	_, err := rc.HandleBatchReportSplit(request)
-	if err != nil {
+	if _, err := rc.HandleBatchReportSplit(request); err != nil {
 		return nil, status.Errorf(codes.Unknown, err.Error())
 	}
 
@@ -625,14 +653,20 @@ func (s *Server) GetClusterConfig(ctx context.Context, request *pdpb.GetClusterC
 	if rc == nil {
 		return &pdpb.GetClusterConfigResponse{Header: s.notBootstrappedHeader()}, nil
 	}
+	// The meta only records the max peer count at bootstrap, use the current one.
+	meta := rc.GetConfig()
+	meta.MaxPeerCount = uint32(s.persistOptions.GetMaxReplicas())
 	return &pdpb.GetClusterConfigResponse{
 		Header:  s.header(),
-		Cluster: rc.GetConfig(),
+		Cluster: meta,
 	}, nil
 }
 
 // PutClusterConfig implements gRPC PDServer.
-func (s *Server) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterConfigRequest) (*pdpb.PutClusterConfigResponse, error) {
+func (s *Server) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterConfigRequest) (resp *pdpb.PutClusterConfigResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "PutClusterConfig", fmt.Sprintf("max-peer-count=%d", request.GetCluster().GetMaxPeerCount()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -654,7 +688,10 @@ func (s *Server) PutClusterConfig(ctx context.Context, request *pdpb.PutClusterC
 }
 
 // ScatterRegion implements gRPC PDServer.
-func (s *Server) ScatterRegion(ctx context.Context, request *pdpb.ScatterRegionRequest) (*pdpb.ScatterRegionResponse, error) {
+func (s *Server) ScatterRegion(ctx context.Context, request *pdpb.ScatterRegionRequest) (resp *pdpb.ScatterRegionResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "ScatterRegion", fmt.Sprintf("region-id=%d", request.GetRegionId()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -720,7 +757,10 @@ func (s *Server) SyncRegions(stream pdpb.PD_SyncRegionsServer) error {
 }
 
 // UpdateGCSafePoint implements gRPC PDServer.
-func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSafePointRequest) (*pdpb.UpdateGCSafePointResponse, error) {
+func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSafePointRequest) (resp *pdpb.UpdateGCSafePointResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "UpdateGCSafePoint", fmt.Sprintf("safe-point=%d", request.GetSafePoint()), start, resp.GetHeader(), err)
+	}(time.Now())
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -758,7 +798,10 @@ func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSa
 }
 
 // UpdateServiceGCSafePoint update the safepoint for specific service
-func (s *Server) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb.UpdateServiceGCSafePointRequest) (*pdpb.UpdateServiceGCSafePointResponse, error) {
+func (s *Server) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb.UpdateServiceGCSafePointRequest) (resp *pdpb.UpdateServiceGCSafePointResponse, err error) {
+	defer func(start time.Time) {
+		s.auditGRPC(ctx, "UpdateServiceGCSafePoint", fmt.Sprintf("service-id=%s ttl=%d safe-point=%d", request.GetServiceId(), request.GetTTL(), request.GetSafePoint()), start, resp.GetHeader(), err)
+	}(time.Now())
 	s.serviceSafePointLock.Lock()
 	defer s.serviceSafePointLock.Unlock()
 
@@ -55,7 +55,7 @@ func (alloc *AllocatorImpl) Alloc() (uint64, error) {
 	defer alloc.mu.Unlock()
 
 	if alloc.base == alloc.end {
-		end, err := alloc.generate()
+		end, err := alloc.generate(0)
 		if err != nil {
 			return 0, err
 		}
@@ -69,7 +69,35 @@ func (alloc *AllocatorImpl) Alloc() (uint64, error) {
 	return alloc.base, nil
 }
 
-func (alloc *AllocatorImpl) generate() (uint64, error) {
+// Rebase makes sure that all IDs allocated later are greater than minID. It is
+// used to fast-forward the allocator when the persisted counter is behind the
+// IDs that are already in use, e.g. after etcd is restored from a backup.
+func (alloc *AllocatorImpl) Rebase(minID uint64) error {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+
+	if minID <= alloc.base {
+		return nil
+	}
+	if minID < alloc.end {
+		alloc.base = minID
+		return nil
+	}
+
+	end, err := alloc.generate(minID)
+	if err != nil {
+		return err
+	}
+	// The persisted counter may be above minID, e.g. IDs reserved by the
+	// previous leader, so always start from the newly reserved batch.
+	alloc.end = end
+	alloc.base = alloc.end - allocStep
+	return nil
+}
+
+// generate reserves the next batch of IDs and returns its end. The batch
+// starts after minID if the persisted counter is smaller than it.
+func (alloc *AllocatorImpl) generate(minID uint64) (uint64, error) {
 	key := alloc.getAllocIDPath()
 	value, err := etcdutil.GetValue(alloc.client, key)
 	if err != nil {
@@ -94,6 +122,11 @@ func (alloc *AllocatorImpl) generate() (uint64, error) {
 		cmp = clientv3.Compare(clientv3.Value(key), "=", string(value))
 	}
 
+	if end < minID {
+		log.Warn("idAllocator is behind the max used id, fast-forward it",
+			zap.Uint64("persisted-id", end), zap.Uint64("max-used-id", minID))
+		end = minID
+	}
 	end += allocStep
 	value = typeutil.Uint64ToBytes(end)
 	txn := kv.NewSlowLogTxn(alloc.client)
@@ -85,6 +85,8 @@ type Server struct {
 
 	// Server state.
 	isServing int64
+	// bootstrapped is set once the cluster meta is found in storage.
+	bootstrapped int32
 
 	// Server start timestamp
 	startTimestamp int64
@@ -124,6 +126,8 @@ type Server struct {
 	cluster *cluster.RaftCluster
 	// For async region heartbeat.
 	hbStreams *heartbeatStreams
+	// for recording the requests which may change the cluster.
+	auditor Auditor
 	// Zap logger
 	lg       *zap.Logger
 	logProps *log.ZapProperties
@@ -220,6 +224,7 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 		ctx:               ctx,
 		startTimestamp:    time.Now().Unix(),
 		DiagnosticsServer: sysutil.NewDiagnosticsServer(cfg.Log.File.Filename),
+		auditor:           LogAuditor{},
 	}
 
 	s.handler = newHandler(s)
@@ -657,6 +662,11 @@ func (s *Server) SetStorage(storage *core.Storage) {
 	s.storage = storage
 }
 
+// GetAuditor returns the auditor of the server.
+func (s *Server) GetAuditor() Auditor {
+	return s.auditor
+}
+
 // GetBasicCluster returns the basic cluster of server.
 func (s *Server) GetBasicCluster() *core.BasicCluster {
 	return s.basicCluster
@@ -979,7 +989,28 @@ func (s *Server) GetRegions() []*core.RegionInfo {
 func (s *Server) GetClusterStatus() (*cluster.Status, error) {
 	s.cluster.Lock()
 	defer s.cluster.Unlock()
-	return s.cluster.LoadClusterStatus()
+	status, err := s.cluster.LoadClusterStatus()
+	if err != nil {
+		return nil, err
+	}
+	status.TsoRequireBootstrap = s.cfg.TsoRequireBootstrap
+	return status, nil
+}
+
+// isClusterBootstrapped checks whether the cluster meta has been persisted. A
+// cluster never goes back to not bootstrapped, so the positive result is cached.
+func (s *Server) isClusterBootstrapped() (bool, error) {
+	if atomic.LoadInt32(&s.bootstrapped) == 1 {
+		return true, nil
+	}
+	ok, err := s.storage.LoadMeta(&metapb.Cluster{})
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		atomic.StoreInt32(&s.bootstrapped, 1)
+	}
+	return ok, nil
 }
 
 // SetLogLevel sets log level.
@@ -22,7 +22,6 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
-	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/election"
 	"go.uber.org/zap"
 )
@@ -110,20 +109,17 @@ func (gta *GlobalTSOAllocator) GenerateTSO(count uint32) (pdpb.Timestamp, error)
 	})
 
 	for i := 0; i < maxRetryCount; i++ {
-		current := (*atomicObject)(atomic.LoadPointer(&gta.timestampOracle.TSO))
-		if current == nil || current.physical == typeutil.ZeroTime {
+		resp.Physical, resp.Logical = gta.timestampOracle.generateTSO(int64(count))
+		if resp.Physical == 0 {
 			// If it's leader, maybe SyncTimestamp hasn't completed yet
 			if gta.getLeadership().Check() {
 				log.Info("sync hasn't completed yet, wait for a while")
 				time.Sleep(200 * time.Millisecond)
 				continue
 			}
-			log.Error("invalid timestamp", zap.Any("timestamp", current), zap.Error(errs.ErrInvalidTimestamp.FastGenByArgs()))
+			log.Error("invalid timestamp", zap.Any("timestamp", resp), zap.Error(errs.ErrInvalidTimestamp.FastGenByArgs()))
 			return pdpb.Timestamp{}, errors.New("can not get timestamp, may be not leader")
 		}
-
-		resp.Physical = current.physical.UnixNano() / int64(time.Millisecond)
-		resp.Logical = atomic.AddInt64(&current.logical, int64(count))
 		if resp.Logical >= maxLogical {
 			log.Error("logical part outside of max logical interval, please check ntp time",
 				zap.Reflect("response", resp),
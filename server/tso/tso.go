@@ -15,9 +15,9 @@ package tso
 
 import (
 	"path"
+	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -42,8 +42,8 @@ const (
 	maxLogical = int64(1 << 18)
 )
 
-// atomicObject is used to store the current TSO in memory.
-type atomicObject struct {
+// tsoObject is used to store the current TSO in memory.
+type tsoObject struct {
 	physical time.Time
 	logical  int64
 }
@@ -55,11 +55,63 @@ type timestampOracle struct {
 	// TODO: remove saveInterval
 	saveInterval  time.Duration
 	maxResetTSGap func() time.Duration
-	// For tso, set after the PD becomes a leader.
-	TSO           unsafe.Pointer
+	// tsoMux is used to protect the current TSO, which is set after the PD becomes a leader.
+	// The physical and logical parts must be changed together under the lock, otherwise a
+	// TSO may be generated from a physical time which has already been replaced and break
+	// the monotonicity across requests.
+	tsoMux struct {
+		sync.RWMutex
+		tso *tsoObject
+	}
 	lastSavedTime atomic.Value
 }
 
+// setTSO sets the current TSO to the given physical time with a zero logical part.
+func (t *timestampOracle) setTSO(physical time.Time) {
+	t.tsoMux.Lock()
+	defer t.tsoMux.Unlock()
+	t.tsoMux.tso = &tsoObject{physical: physical}
+}
+
+// setTSOPhysical updates the physical part and resets the logical part of the current TSO.
+// It will not update if the given physical time is not greater than the current one.
+func (t *timestampOracle) setTSOPhysical(next time.Time) {
+	t.tsoMux.Lock()
+	defer t.tsoMux.Unlock()
+	if t.tsoMux.tso == nil {
+		return
+	}
+	if typeutil.SubTimeByWallClock(next, t.tsoMux.tso.physical) > 0 {
+		t.tsoMux.tso.physical = next
+		t.tsoMux.tso.logical = 0
+	}
+}
+
+// getTSO returns the physical and logical parts of the current TSO.
+func (t *timestampOracle) getTSO() (time.Time, int64) {
+	t.tsoMux.RLock()
+	defer t.tsoMux.RUnlock()
+	if t.tsoMux.tso == nil {
+		return typeutil.ZeroTime, 0
+	}
+	return t.tsoMux.tso.physical, t.tsoMux.tso.logical
+}
+
+// generateTSO reserves count logical timestamps and returns the physical part
+// in milliseconds and the largest reserved logical part. It returns a zero
+// physical part if the TSO has not been synchronized yet.
+func (t *timestampOracle) generateTSO(count int64) (physical int64, logical int64) {
+	t.tsoMux.Lock()
+	defer t.tsoMux.Unlock()
+	if t.tsoMux.tso == nil || t.tsoMux.tso.physical == typeutil.ZeroTime {
+		return 0, 0
+	}
+	physical = t.tsoMux.tso.physical.UnixNano() / int64(time.Millisecond)
+	t.tsoMux.tso.logical += count
+	logical = t.tsoMux.tso.logical
+	return physical, logical
+}
+
 func (t *timestampOracle) getTimestampPath() string {
 	return path.Join(t.rootPath, "timestamp")
 }
@@ -129,10 +181,7 @@ func (t *timestampOracle) SyncTimestamp(leadership *election.Leadership) error {
 	tsoCounter.WithLabelValues("sync_ok").Inc()
 	log.Info("sync and save timestamp", zap.Time("last", last), zap.Time("save", save), zap.Time("next", next))
 
-	current := &atomicObject{
-		physical: next,
-	}
-	atomic.StorePointer(&t.TSO, unsafe.Pointer(current))
+	t.setTSO(next)
 
 	return nil
 }
@@ -145,15 +194,15 @@ func (t *timestampOracle) ResetUserTimestamp(leadership *election.Leadership, ts
 	}
 	physical, _ := tsoutil.ParseTS(tso)
 	next := physical.Add(time.Millisecond)
-	prev := (*atomicObject)(atomic.LoadPointer(&t.TSO))
+	prevPhysical, _ := t.getTSO()
 
 	// do not update
-	if typeutil.SubTimeByWallClock(next, prev.physical) <= 3*updateTimestampGuard {
+	if typeutil.SubTimeByWallClock(next, prevPhysical) <= 3*updateTimestampGuard {
 		tsoCounter.WithLabelValues("err_reset_small_ts").Inc()
 		return errors.New("the specified ts too small than now")
 	}
 
-	if typeutil.SubTimeByWallClock(next, prev.physical) >= t.maxResetTSGap() {
+	if typeutil.SubTimeByWallClock(next, prevPhysical) >= t.maxResetTSGap() {
 		tsoCounter.WithLabelValues("err_reset_large_ts").Inc()
 		return errors.New("the specified ts too large than now")
 	}
@@ -163,10 +212,7 @@ func (t *timestampOracle) ResetUserTimestamp(leadership *election.Leadership, ts
 		tsoCounter.WithLabelValues("err_save_reset_ts").Inc()
 		return err
 	}
-	update := &atomicObject{
-		physical: next,
-	}
-	atomic.CompareAndSwapPointer(&t.TSO, unsafe.Pointer(prev), unsafe.Pointer(update))
+	t.setTSOPhysical(next)
 	tsoCounter.WithLabelValues("reset_tso_ok").Inc()
 	return nil
 }
@@ -183,7 +229,7 @@ func (t *timestampOracle) ResetUserTimestamp(leadership *election.Leadership, ts
 // 2. The physical time is monotonically increasing.
 // 3. The physical time is always less than the saved timestamp.
 func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error {
-	prev := (*atomicObject)(atomic.LoadPointer(&t.TSO))
+	prevPhysical, prevLogical := t.getTSO()
 	now := time.Now()
 
 	failpoint.Inject("fallBackUpdate", func() {
@@ -192,9 +238,9 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 
 	tsoCounter.WithLabelValues("save").Inc()
 
-	jetLag := typeutil.SubTimeByWallClock(now, prev.physical)
+	jetLag := typeutil.SubTimeByWallClock(now, prevPhysical)
 	if jetLag > 3*UpdateTimestampStep {
-		log.Warn("clock offset", zap.Duration("jet-lag", jetLag), zap.Time("prev-physical", prev.physical), zap.Time("now", now))
+		log.Warn("clock offset", zap.Duration("jet-lag", jetLag), zap.Time("prev-physical", prevPhysical), zap.Time("now", now))
 		tsoCounter.WithLabelValues("slow_save").Inc()
 	}
 
@@ -203,7 +249,6 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 	}
 
 	var next time.Time
-	prevLogical := atomic.LoadInt64(&prev.logical)
 	// If the system time is greater, it will be synchronized with the system time.
 	if jetLag > updateTimestampGuard {
 		next = now
@@ -211,7 +256,7 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 		// The reason choosing maxLogical/2 here is that it's big enough for common cases.
 		// Because there is enough timestamp can be allocated before next update.
 		log.Warn("the logical time may be not enough", zap.Int64("prev-logical", prevLogical))
-		next = prev.physical.Add(time.Millisecond)
+		next = prevPhysical.Add(time.Millisecond)
 	} else {
 		// It will still use the previous physical time to alloc the timestamp.
 		tsoCounter.WithLabelValues("skip_save").Inc()
@@ -228,12 +273,7 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 		}
 	}
 
-	current := &atomicObject{
-		physical: next,
-		logical:  0,
-	}
-
-	atomic.StorePointer(&t.TSO, unsafe.Pointer(current))
+	t.setTSOPhysical(next)
 	tsoGauge.WithLabelValues("tso").Set(float64(next.Unix()))
 
 	return nil
@@ -241,8 +281,5 @@ func (t *timestampOracle) UpdateTimestamp(leadership *election.Leadership) error
 
 // ResetTimestamp is used to reset the timestamp.
 func (t *timestampOracle) ResetTimestamp() {
-	zero := &atomicObject{
-		physical: typeutil.ZeroTime,
-	}
-	atomic.StorePointer(&t.TSO, unsafe.Pointer(zero))
+	t.setTSO(typeutil.ZeroTime)
 }
@@ -20,11 +20,13 @@ import (
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -32,6 +34,7 @@ import (
 	"github.com/tikv/pd/pkg/mock/mockid"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/tests"
 	"go.etcd.io/etcd/clientv3"
@@ -62,6 +65,95 @@ func (s *clientTestSuite) TearDownSuite(c *C) {
 	s.cancel()
 }
 
+func (s *clientTestSuite) TestGetClusterConfigNotBootstrapped(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	c.Assert(err, IsNil)
+	defer cluster.Destroy()
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	leader := cluster.WaitLeader()
+
+	cli, err := pd.NewClientWithContext(s.ctx, []string{cluster.GetServer(leader).GetAddr()}, pd.SecurityOption{})
+	c.Assert(err, IsNil)
+	defer cli.Close()
+
+	_, err = cli.GetClusterConfig(context.Background())
+	c.Assert(errors.Cause(err), Equals, pd.ErrClusterNotBootstrapped)
+}
+
+func (s *clientTestSuite) TestTSORequireBootstrap(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config) { conf.TsoRequireBootstrap = true })
+	c.Assert(err, IsNil)
+	defer cluster.Destroy()
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	leaderServer := cluster.GetServer(cluster.WaitLeader())
+
+	cli, err := pd.NewClientWithContext(s.ctx, []string{leaderServer.GetAddr()}, pd.SecurityOption{})
+	c.Assert(err, IsNil)
+	defer cli.Close()
+
+	_, _, err = cli.GetTS(context.Background())
+	c.Assert(errors.Cause(err), Equals, pd.ErrClusterNotBootstrapped)
+
+	c.Assert(leaderServer.BootstrapCluster(), IsNil)
+	testutil.WaitUntil(c, func(c *C) bool {
+		physical, _, err := cli.GetTS(context.Background())
+		return err == nil && physical > 0
+	})
+}
+
+func (s *clientTestSuite) TestTSOStreamFailure(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	c.Assert(err, IsNil)
+	defer cluster.Destroy()
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	leaderServer := cluster.GetServer(cluster.WaitLeader())
+
+	cli, err := pd.NewClientWithContext(s.ctx, []string{leaderServer.GetAddr()}, pd.SecurityOption{})
+	c.Assert(err, IsNil)
+	defer cli.Close()
+
+	// Break the tso stream randomly while requests are in flight, the client
+	// recreates the stream and the failed requests must not get a timestamp.
+	c.Assert(failpoint.Enable("github.com/tikv/pd/client/tsoStreamRecvFail", "30%return(true)"), IsNil)
+	var (
+		wg       sync.WaitGroup
+		failures int32
+	)
+	count := 10
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func() {
+			defer wg.Done()
+			var last int64
+			for i := 0; i < 100; i++ {
+				physical, logical, err := cli.GetTS(context.Background())
+				if err != nil {
+					atomic.AddInt32(&failures, 1)
+					continue
+				}
+				c.Assert(physical, Greater, int64(0))
+				ts := physical<<18 + logical
+				c.Assert(ts, Greater, last)
+				last = ts
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(failpoint.Disable("github.com/tikv/pd/client/tsoStreamRecvFail"), IsNil)
+	c.Assert(atomic.LoadInt32(&failures), Greater, int32(0))
+
+	testutil.WaitUntil(c, func(c *C) bool {
+		physical, _, err := cli.GetTS(context.Background())
+		return err == nil && physical > 0
+	})
+}
+
 type client interface {
 	GetLeaderAddr() string
 	ScheduleCheckLeader()
@@ -551,6 +643,25 @@ func (s *testClientSuite) TestGetRegionByID(c *C) {
 	c.Succeed()
 }
 
+func (s *testClientSuite) TestGetClusterConfig(c *C) {
+	meta, err := s.client.GetClusterConfig(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(meta.GetId(), Equals, s.srv.ClusterID())
+	c.Assert(meta.GetMaxPeerCount(), Equals, uint32(s.srv.GetConfig().Replication.MaxReplicas))
+
+	// The max peer count follows the replication config.
+	old := s.srv.GetReplicationConfig()
+	cfg := *old
+	cfg.MaxReplicas = 5
+	c.Assert(s.srv.SetReplicationConfig(cfg), IsNil)
+	defer func() {
+		c.Assert(s.srv.SetReplicationConfig(*old), IsNil)
+	}()
+	meta, err = s.client.GetClusterConfig(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(meta.GetMaxPeerCount(), Equals, uint32(5))
+}
+
 func (s *testClientSuite) TestGetStore(c *C) {
 	cluster := s.srv.GetRaftCluster()
 	c.Assert(cluster, NotNil)
@@ -673,7 +673,7 @@ func (s *clusterTestSuite) TestLoadClusterInfo(c *C) {
 	regions := make([]*metapb.Region, 0, n)
 	for i := uint64(0); i < uint64(n); i++ {
 		region := &metapb.Region{
-			Id:          i,
+			Id:          i + 1,
 			StartKey:    []byte(fmt.Sprintf("%20d", i)),
 			EndKey:      []byte(fmt.Sprintf("%20d", i+1)),
 			RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1},
@@ -700,14 +700,14 @@ func (s *clusterTestSuite) TestLoadClusterInfo(c *C) {
 	}
 	c.Assert(raftCluster.GetRegionCount(), Equals, n)
 	for _, region := range raftCluster.GetMetaRegions() {
-		c.Assert(region, DeepEquals, regions[region.GetId()])
+		c.Assert(region, DeepEquals, regions[region.GetId()-1])
 	}
 
 	m := 20
 	regions = make([]*metapb.Region, 0, n)
 	for i := uint64(0); i < uint64(m); i++ {
 		region := &metapb.Region{
-			Id:          i,
+			Id:          i + 1,
 			StartKey:    []byte(fmt.Sprintf("%20d", i)),
 			EndKey:      []byte(fmt.Sprintf("%20d", i+1)),
 			RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1},
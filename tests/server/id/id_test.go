@@ -15,13 +15,19 @@ package id_test
 
 import (
 	"context"
+	"path"
+	"strconv"
 	"sync"
 	"testing"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/id"
 	"github.com/tikv/pd/tests"
 	"go.uber.org/goleak"
 )
@@ -95,6 +101,114 @@ func (s *testAllocIDSuite) TestID(c *C) {
 	wg.Wait()
 }
 
+func (s *testAllocIDSuite) TestRebase(c *C) {
+	var err error
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	alloc := cluster.GetServer(cluster.GetLeader()).GetAllocator()
+	id, err := alloc.Alloc()
+	c.Assert(err, IsNil)
+
+	// Rebase to a smaller ID takes no effect.
+	c.Assert(alloc.Rebase(id-1), IsNil)
+	next, err := alloc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, id+1)
+
+	// Rebase within the current batch.
+	c.Assert(alloc.Rebase(id+10), IsNil)
+	next, err = alloc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, id+11)
+
+	// Rebase beyond the persisted counter.
+	maxID := id + 10*allocStep
+	c.Assert(alloc.Rebase(maxID), IsNil)
+	for i := uint64(0); i < 2*allocStep; i++ {
+		next, err = alloc.Alloc()
+		c.Assert(err, IsNil)
+		c.Assert(next, Equals, maxID+i+1)
+	}
+}
+
+func (s *testAllocIDSuite) TestRebaseBelowPersisted(c *C) {
+	var err error
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	// The current allocator reserves [1, allocStep] in etcd.
+	_, err = leaderServer.GetAllocator().Alloc()
+	c.Assert(err, IsNil)
+
+	// A fresh allocator, like the one of a new leader, must not reuse any
+	// reserved ID even if the max used ID is below the persisted counter.
+	rootPath := path.Join("/pd", strconv.FormatUint(leaderServer.GetClusterID(), 10))
+	alloc := id.NewAllocatorImpl(leaderServer.GetEtcdClient(), rootPath, leaderServer.GetServer().GetMember().MemberValue())
+	c.Assert(alloc.Rebase(allocStep/2), IsNil)
+	next, err := alloc.Alloc()
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, allocStep+1)
+}
+
+func (s *testAllocIDSuite) TestRebaseOnLeaderChange(c *C) {
+	var err error
+	// Keep the regions in etcd, so the new leader loads the saved region.
+	cluster, err := tests.NewTestCluster(s.ctx, 3, func(conf *config.Config) { conf.PDServerCfg.UseRegionStorage = false })
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	c.Assert(leaderServer.BootstrapCluster(), IsNil)
+
+	// A peer created before etcd is restored from a backup.
+	maxID := 10 * allocStep
+	region := &metapb.Region{
+		Id:          2,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 2, Version: 1},
+		Peers: []*metapb.Peer{
+			{Id: 3, StoreId: 1},
+			{Id: maxID, StoreId: 1},
+		},
+	}
+	c.Assert(leaderServer.GetServer().GetStorage().SaveRegion(region), IsNil)
+
+	// Rewind the persisted counter.
+	rootPath := path.Join("/pd", strconv.FormatUint(leaderServer.GetClusterID(), 10))
+	_, err = leaderServer.GetEtcdClient().Put(context.Background(), path.Join(rootPath, "alloc_id"), string(typeutil.Uint64ToBytes(1)))
+	c.Assert(err, IsNil)
+
+	// The new leader fast-forwards the allocator when the raft cluster starts.
+	c.Assert(leaderServer.Stop(), IsNil)
+	leaderServer = cluster.GetServer(cluster.WaitLeader())
+	testutil.WaitUntil(c, func(c *C) bool {
+		return leaderServer.GetRaftCluster() != nil
+	})
+
+	req := &pdpb.AllocIDRequest{
+		Header: testutil.NewRequestHeader(leaderServer.GetClusterID()),
+	}
+	grpcPDClient := testutil.MustNewGrpcClient(c, leaderServer.GetAddr())
+	resp, err := grpcPDClient.AllocID(context.Background(), req)
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetId(), Greater, maxID)
+}
+
 func (s *testAllocIDSuite) TestCommand(c *C) {
 	var err error
 	cluster, err := tests.NewTestCluster(s.ctx, 1)
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/tests"
 	"go.uber.org/goleak"
 )
@@ -116,6 +117,106 @@ func (s *testTsoSuite) TestTso(c *C) {
 	wg.Wait()
 }
 
+func (s *testTsoSuite) TestTsoRequireBootstrap(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1, func(conf *config.Config) { conf.TsoRequireBootstrap = true })
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	grpcPDClient := testutil.MustNewGrpcClient(c, leaderServer.GetAddr())
+	req := &pdpb.TsoRequest{
+		Header: testutil.NewRequestHeader(leaderServer.GetClusterID()),
+		Count:  1,
+	}
+
+	getTS := func() (*pdpb.TsoResponse, error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		tsoClient, err := grpcPDClient.Tso(ctx)
+		c.Assert(err, IsNil)
+		defer tsoClient.CloseSend()
+		c.Assert(tsoClient.Send(req), IsNil)
+		return tsoClient.Recv()
+	}
+
+	resp, err := getTS()
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetHeader().GetError().GetType(), Equals, pdpb.ErrorType_NOT_BOOTSTRAPPED)
+	c.Assert(resp.GetTimestamp(), IsNil)
+
+	c.Assert(leaderServer.BootstrapCluster(), IsNil)
+	resp, err = getTS()
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetHeader().GetError(), IsNil)
+	c.Assert(resp.GetTimestamp().GetPhysical(), Greater, int64(0))
+}
+
+func (s *testTsoSuite) TestTsoMonotonicAcrossStreams(c *C) {
+	cluster, err := tests.NewTestCluster(s.ctx, 1)
+	defer cluster.Destroy()
+	c.Assert(err, IsNil)
+
+	err = cluster.RunInitialServers()
+	c.Assert(err, IsNil)
+	cluster.WaitLeader()
+
+	leaderServer := cluster.GetServer(cluster.GetLeader())
+	grpcPDClient := testutil.MustNewGrpcClient(c, leaderServer.GetAddr())
+	clusterID := leaderServer.GetClusterID()
+
+	// A timestamp must be greater than any timestamp which has been returned
+	// to any stream before it is requested.
+	var (
+		mu   sync.Mutex
+		last pdpb.Timestamp
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tsoClient, err := grpcPDClient.Tso(ctx)
+			c.Assert(err, IsNil)
+			defer tsoClient.CloseSend()
+			for j := 0; j < 500; j++ {
+				mu.Lock()
+				before := last
+				mu.Unlock()
+
+				err = tsoClient.Send(&pdpb.TsoRequest{
+					Header: testutil.NewRequestHeader(clusterID),
+					Count:  1,
+				})
+				c.Assert(err, IsNil)
+				resp, err := tsoClient.Recv()
+				c.Assert(err, IsNil)
+				ts := *resp.GetTimestamp()
+				c.Assert(tsLess(before, ts), IsTrue, Commentf("before: %v, ts: %v", before, ts))
+
+				mu.Lock()
+				if tsLess(last, ts) {
+					last = ts
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func tsLess(ts1, ts2 pdpb.Timestamp) bool {
+	if ts1.GetPhysical() == ts2.GetPhysical() {
+		return ts1.GetLogical() < ts2.GetLogical()
+	}
+	return ts1.GetPhysical() < ts2.GetPhysical()
+}
+
 func (s *testTsoSuite) TestConcurrcyRequest(c *C) {
 	cluster, err := tests.NewTestCluster(s.ctx, 1)
 	defer cluster.Destroy()